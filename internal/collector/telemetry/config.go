@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+const telemetryCfgKey = "telemetry"
+
+// Config is the configuration of the telemetry package, decodable from the top-level
+// "telemetry" key of the collector's YAML config the same way a receiver or exporter
+// config is. It lets operators express per-signal levels, the metric readers that should
+// be active, and the views that reshape instruments at Detailed level, instead of relying
+// solely on command-line flags.
+type Config struct {
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	Traces  SignalConfig  `mapstructure:"traces"`
+	Logs    SignalConfig  `mapstructure:"logs"`
+
+	// AddInstanceID controls whether a fresh 'service.instance.id' is attached to the
+	// collector's own metrics and traces.
+	AddInstanceID bool `mapstructure:"add_instance_id"`
+}
+
+// SignalConfig is the common shape shared by traces and logs: a level and, for signals
+// that can be pushed out via OTLP, the exporter to use and its connection settings.
+type SignalConfig struct {
+	Level    string     `mapstructure:"level"`
+	Exporter string     `mapstructure:"exporter"`
+	Otlp     OtlpConfig `mapstructure:"otlp"`
+}
+
+// MetricsConfig is metrics' own shape, not SignalConfig: metrics can have several readers
+// active side by side (e.g. a Prometheus scrape endpoint and an OTLP push at once), each
+// with its own exporter and connection settings, so there is no single top-level
+// exporter/otlp pair the way traces and logs have. Readers lists which ones are active;
+// Views reshapes instruments once Level reaches Detailed.
+type MetricsConfig struct {
+	Level string `mapstructure:"level"`
+
+	Readers []ReaderConfig `mapstructure:"readers"`
+	Views   []ViewConfig   `mapstructure:"views"`
+}
+
+// ReaderConfig configures a single metrics reader: "prometheus" exposes a scrape
+// endpoint at Address, "otlp" pushes to an OTLP backend described by OtlpConfig, and
+// "stdout" writes metrics to standard output for local debugging.
+type ReaderConfig struct {
+	Type    string `mapstructure:"type"`
+	Address string `mapstructure:"address"`
+
+	OtlpConfig `mapstructure:",squash"`
+}
+
+// OtlpConfig describes how to reach an OTLP-compatible backend for self-telemetry.
+type OtlpConfig struct {
+	Endpoint    string            `mapstructure:"endpoint"`
+	Headers     map[string]string `mapstructure:"headers"`
+	Insecure    bool              `mapstructure:"insecure"`
+	Compression string            `mapstructure:"compression"`
+	Protocol    string            `mapstructure:"protocol"`
+}
+
+// ViewConfig registers an OTel SDK metric view at Detailed level: it can rename an
+// instrument, drop attributes to bound cardinality, or override histogram bucket
+// boundaries, e.g. tighter buckets for otelcol_receiver_accepted_spans latencies.
+type ViewConfig struct {
+	Instrument     string    `mapstructure:"instrument"`
+	Rename         string    `mapstructure:"rename"`
+	DropAttributes []string  `mapstructure:"drop_attributes"`
+	Buckets        []float64 `mapstructure:"buckets"`
+}
+
+// DefaultConfig returns the telemetry configuration matching the collector's historical,
+// flag-only behavior: Prometheus scraping at GetMetricsAddrDefault, self-tracing off.
+func DefaultConfig() *Config {
+	return &Config{
+		Metrics: MetricsConfig{
+			Level: "basic",
+			Readers: []ReaderConfig{
+				{Type: "prometheus", Address: GetMetricsAddrDefault()},
+			},
+		},
+		Traces:        SignalConfig{Level: "none"},
+		Logs:          SignalConfig{Level: "basic"},
+		AddInstanceID: true,
+	}
+}
+
+// LoadFromViper decodes the "telemetry" key of v into a Config, layered on top of
+// DefaultConfig so a YAML file only needs to mention the fields it overrides. The
+// returned Config is what NewMeterProvider and NewTracerProvider take; the service
+// startup path in this repository does not call LoadFromViper.
+func LoadFromViper(v *viper.Viper) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if v.IsSet(telemetryCfgKey) {
+		errorOnUnusedKeys := func(c *mapstructure.DecoderConfig) { c.ErrorUnused = true }
+		if err := v.UnmarshalKey(telemetryCfgKey, cfg, errorOnUnusedKeys); err != nil {
+			// Surfaces e.g. a stray "telemetry.metrics.exporter"/"telemetry.metrics.otlp",
+			// which would otherwise be silently ignored: metrics has no single
+			// exporter/otlp pair, only MetricsConfig.Readers.
+			return nil, fmt.Errorf("failed to load telemetry config: %w", err)
+		}
+	}
+
+	for _, level := range []string{cfg.Metrics.Level, cfg.Traces.Level, cfg.Logs.Level} {
+		if _, err := levelFromString(level); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// ApplyFlags overlays onto cfg whatever telemetry flags the user explicitly passed on the
+// command line, so deployments that have not moved to config-file telemetry keep working
+// unchanged. Flags() must have been called, and flags.Parse must have already run, before
+// this is called.
+func (cfg *Config) ApplyFlags(flags *flag.FlagSet) {
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case metricsLevelCfg:
+			cfg.Metrics.Level = *metricsLevelPtr
+		case metricsAddrCfg:
+			cfg.prometheusReader().Address = *metricsAddrPtr
+		case metricsExporterCfg:
+			cfg.setMetricsReaders(GetMetricsExporter())
+		case tracesExporterCfg:
+			cfg.Traces.Exporter = firstOrEmpty(GetTracesExporter())
+		case otlpEndpointCfg:
+			cfg.Traces.Otlp.Endpoint = *otlpEndpointPtr
+			cfg.otlpReader().Endpoint = *otlpEndpointPtr
+		case otlpHeadersCfg:
+			headers := GetOtlpHeaders()
+			cfg.Traces.Otlp.Headers = headers
+			cfg.otlpReader().Headers = headers
+		case otlpInsecureCfg:
+			cfg.Traces.Otlp.Insecure = *otlpInsecurePtr
+			cfg.otlpReader().Insecure = *otlpInsecurePtr
+		case otlpCompressionCfg:
+			cfg.Traces.Otlp.Compression = *otlpCompressionPtr
+			cfg.otlpReader().Compression = *otlpCompressionPtr
+		case otlpProtocolCfg:
+			cfg.Traces.Otlp.Protocol = *otlpProtocolPtr
+			cfg.otlpReader().Protocol = *otlpProtocolPtr
+		case addInstanceIDCfg:
+			cfg.AddInstanceID = *addInstanceIDPtr
+		}
+	})
+}
+
+// setMetricsReaders replaces cfg.Metrics.Readers with one entry per name in names,
+// reusing any existing reader config of the same type so its connection settings survive.
+func (cfg *Config) setMetricsReaders(names []string) {
+	existing := cfg.Metrics.Readers
+	cfg.Metrics.Readers = nil
+	for _, name := range names {
+		reader := ReaderConfig{Type: name}
+		for _, e := range existing {
+			if e.Type == name {
+				reader = e
+				break
+			}
+		}
+		cfg.Metrics.Readers = append(cfg.Metrics.Readers, reader)
+	}
+}
+
+// prometheusReader returns the Metrics reader config of type "prometheus", creating one
+// if none is present yet.
+func (cfg *Config) prometheusReader() *ReaderConfig {
+	return cfg.readerOfType("prometheus")
+}
+
+// otlpReader returns the Metrics reader config of type "otlp", creating one if none is
+// present yet.
+func (cfg *Config) otlpReader() *ReaderConfig {
+	return cfg.readerOfType("otlp")
+}
+
+func (cfg *Config) readerOfType(typ string) *ReaderConfig {
+	for i := range cfg.Metrics.Readers {
+		if cfg.Metrics.Readers[i].Type == typ {
+			return &cfg.Metrics.Readers[i]
+		}
+	}
+	cfg.Metrics.Readers = append(cfg.Metrics.Readers, ReaderConfig{Type: typ})
+	return &cfg.Metrics.Readers[len(cfg.Metrics.Readers)-1]
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    Level
+		wantErr bool
+	}{
+		{name: "empty defaults to basic", str: "", want: Basic},
+		{name: "basic", str: "basic", want: Basic},
+		{name: "case insensitive", str: "DETAILED", want: Detailed},
+		{name: "none", str: "none", want: None},
+		{name: "normal", str: "normal", want: Normal},
+		{name: "unknown", str: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := levelFromString(tt.str)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	assert.Equal(t, "basic", cfg.Metrics.Level)
+	require.Len(t, cfg.Metrics.Readers, 1)
+	assert.Equal(t, "prometheus", cfg.Metrics.Readers[0].Type)
+	assert.Equal(t, "none", cfg.Traces.Level)
+	assert.Equal(t, "basic", cfg.Logs.Level)
+	assert.True(t, cfg.AddInstanceID)
+}
+
+func TestLoadFromViper(t *testing.T) {
+	t.Run("no telemetry key falls back to defaults", func(t *testing.T) {
+		cfg, err := LoadFromViper(viper.New())
+		require.NoError(t, err)
+		assert.Equal(t, DefaultConfig(), cfg)
+	})
+
+	t.Run("overrides layer on top of defaults", func(t *testing.T) {
+		v := viper.New()
+		v.Set("telemetry.metrics.level", "detailed")
+		v.Set("telemetry.traces.level", "basic")
+		v.Set("telemetry.traces.exporter", "otlp")
+		v.Set("telemetry.traces.otlp.endpoint", "localhost:4317")
+
+		cfg, err := LoadFromViper(v)
+		require.NoError(t, err)
+		assert.Equal(t, "detailed", cfg.Metrics.Level)
+		assert.Equal(t, "otlp", cfg.Traces.Exporter)
+		assert.Equal(t, "localhost:4317", cfg.Traces.Otlp.Endpoint)
+		// Defaults not touched by the override are preserved.
+		require.Len(t, cfg.Metrics.Readers, 1)
+		assert.Equal(t, "prometheus", cfg.Metrics.Readers[0].Type)
+	})
+
+	t.Run("invalid level is rejected", func(t *testing.T) {
+		v := viper.New()
+		v.Set("telemetry.metrics.level", "bogus")
+
+		_, err := LoadFromViper(v)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown metrics key is rejected", func(t *testing.T) {
+		v := viper.New()
+		v.Set("telemetry.metrics.exporter", "otlp")
+
+		_, err := LoadFromViper(v)
+		assert.Error(t, err)
+	})
+}
+
+func TestViewsFromConfig(t *testing.T) {
+	t.Run("builds a view per entry", func(t *testing.T) {
+		opts, err := viewsFromConfig([]ViewConfig{
+			{Instrument: "otelcol_receiver_accepted_spans", Buckets: []float64{1, 5, 10}},
+			{Instrument: "otelcol_exporter_sent_spans", Rename: "exporter_sent_spans"},
+		})
+		require.NoError(t, err)
+		assert.Len(t, opts, 2)
+	})
+
+	t.Run("missing instrument selector is rejected", func(t *testing.T) {
+		_, err := viewsFromConfig([]ViewConfig{{Rename: "foo"}})
+		assert.Error(t, err)
+	})
+}
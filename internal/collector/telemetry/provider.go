@@ -0,0 +1,349 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/collector/internal/version"
+)
+
+// shutdownFuncs accumulates the shutdown hooks of every provider NewMeterProvider and
+// NewTracerProvider hand out, so Shutdown can flush pending OTLP batches on collector stop.
+var shutdownFuncs []func(context.Context) error
+
+// NewMeterProvider builds the MeterProvider the collector uses to record its own metrics,
+// honoring cfg.Metrics.Level and the readers it lists. Readers run side by side, so e.g. a
+// Prometheus scrape endpoint and an OTLP push can both be active at once.
+func NewMeterProvider(cfg *Config) (metric.MeterProvider, error) {
+	level, err := levelFromString(cfg.Metrics.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// At None no reader is built, so nothing is served or pushed.
+	if level == None {
+		mp := sdkmetric.NewMeterProvider(sdkmetric.WithResource(res))
+		shutdownFuncs = append(shutdownFuncs, mp.Shutdown)
+		return mp, nil
+	}
+
+	var readers []sdkmetric.Reader
+	for _, readerCfg := range cfg.Metrics.Readers {
+		reader, rErr := newMetricReader(readerCfg)
+		if rErr != nil {
+			return nil, rErr
+		}
+		if reader != nil {
+			readers = append(readers, reader)
+		}
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, reader := range readers {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	if level == Detailed {
+		views, vErr := viewsFromConfig(cfg.Metrics.Views)
+		if vErr != nil {
+			return nil, vErr
+		}
+		opts = append(opts, views...)
+	}
+
+	mp := sdkmetric.NewMeterProvider(opts...)
+	shutdownFuncs = append(shutdownFuncs, mp.Shutdown)
+	return mp, nil
+}
+
+// NewTracerProvider builds the TracerProvider that callers can use to emit spans about the
+// collector's own receiver/exporter/processor work. Nothing in this tree instruments those
+// call sites yet, so the provider goes unused until that lands. It returns a no-op provider
+// when cfg.Traces.Exporter is empty.
+func NewTracerProvider(cfg *Config) (*sdktrace.TracerProvider, error) {
+	if cfg.Traces.Exporter == "" {
+		return sdktrace.NewTracerProvider(), nil
+	}
+	if cfg.Traces.Exporter != "otlp" {
+		return nil, fmt.Errorf("unknown traces exporter %q", cfg.Traces.Exporter)
+	}
+	if cfg.Traces.Otlp.Endpoint == "" {
+		return nil, fmt.Errorf("traces exporter %q requires an otlp endpoint to be set", cfg.Traces.Exporter)
+	}
+
+	res, err := newResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	traceExp, err := newOtlpSpanExporter(cfg.Traces.Otlp)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(traceExp),
+	)
+	shutdownFuncs = append(shutdownFuncs, tp.Shutdown)
+	return tp, nil
+}
+
+// Shutdown flushes and stops every OTLP/Prometheus reader or exporter handed out by
+// NewMeterProvider and NewTracerProvider. The service in this repository does not call it on
+// collector stop; pending OTLP batches are flushed only when a caller invokes Shutdown.
+func Shutdown(ctx context.Context) error {
+	var lastErr error
+	for _, fn := range shutdownFuncs {
+		if err := fn(ctx); err != nil {
+			lastErr = err
+		}
+	}
+	shutdownFuncs = nil
+	return lastErr
+}
+
+// selfTelemetryServiceName is the fixed service.name attached to the collector's own
+// metrics and traces. It is intentionally not derived from the metrics-prefix flag, whose
+// documented purpose is naming instruments, not identifying the service.
+const selfTelemetryServiceName = "otelcol"
+
+// newResource attaches service.name=otelcol, service.version (from internal/version), and,
+// when cfg.AddInstanceID is set, a fresh service.instance.id to every emitted data point.
+// It reads only from cfg, not from the package's flag-backed getters, so it works the same
+// whether the caller went through Flags()+ApplyFlags or LoadFromViper alone.
+func newResource(cfg *Config) (*resource.Resource, error) {
+	attrs := []resource.Option{
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(selfTelemetryServiceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		),
+	}
+	if cfg.AddInstanceID {
+		attrs = append(attrs, resource.WithAttributes(semconv.ServiceInstanceIDKey.String(newInstanceID())))
+	}
+	return resource.New(context.Background(), attrs...)
+}
+
+// newMetricReader builds the sdkmetric.Reader for a single entry of cfg.Metrics.Readers. It
+// returns a nil reader, rather than an error, for an otlp reader with no endpoint configured,
+// so a partially-filled-in config doesn't block the other readers from starting.
+func newMetricReader(cfg ReaderConfig) (sdkmetric.Reader, error) {
+	switch cfg.Type {
+	case "prometheus":
+		return newPrometheusReader(cfg.Address)
+	case "otlp":
+		if cfg.Endpoint == "" {
+			return nil, nil
+		}
+		return newOtlpMetricReader(cfg.OtlpConfig)
+	case "stdout":
+		return newStdoutMetricReader()
+	default:
+		return nil, fmt.Errorf("unknown metrics reader type %q", cfg.Type)
+	}
+}
+
+func newOtlpMetricReader(cfg OtlpConfig) (sdkmetric.Reader, error) {
+	var exp sdkmetric.Exporter
+	var err error
+
+	ctx := context.Background()
+	switch cfg.Protocol {
+	case "http/protobuf":
+		exp, err = otlpmetrichttp.New(ctx, otlpMetricHTTPOptions(cfg)...)
+	case "grpc", "":
+		exp, err = otlpmetricgrpc.New(ctx, otlpMetricGRPCOptions(cfg)...)
+	default:
+		return nil, fmt.Errorf("unknown otlp protocol %q", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewPeriodicReader(exp), nil
+}
+
+func newOtlpSpanExporter(cfg OtlpConfig) (*otlptrace.Exporter, error) {
+	ctx := context.Background()
+	switch cfg.Protocol {
+	case "http/protobuf":
+		return otlptracehttp.New(ctx, otlpTraceHTTPOptions(cfg)...)
+	case "grpc", "":
+		return otlptracegrpc.New(ctx, otlpTraceGRPCOptions(cfg)...)
+	default:
+		return nil, fmt.Errorf("unknown otlp protocol %q", cfg.Protocol)
+	}
+}
+
+func otlpMetricGRPCOptions(cfg OtlpConfig) []otlpmetricgrpc.Option {
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression != "" {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(cfg.Compression))
+	}
+	return opts
+}
+
+func otlpMetricHTTPOptions(cfg OtlpConfig) []otlpmetrichttp.Option {
+	opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == "gzip" {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+	return opts
+}
+
+func otlpTraceGRPCOptions(cfg OtlpConfig) []otlptracegrpc.Option {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression != "" {
+		opts = append(opts, otlptracegrpc.WithCompressor(cfg.Compression))
+	}
+	return opts
+}
+
+func otlpTraceHTTPOptions(cfg OtlpConfig) []otlptracehttp.Option {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == "gzip" {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	return opts
+}
+
+// newPrometheusReader builds the Prometheus scrape reader and serves it on addr, preserving
+// the pre-existing default behavior when no other metrics reader is configured.
+func newPrometheusReader(addr string) (sdkmetric.Reader, error) {
+	exp, err := prometheus.New()
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp.Collector())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("telemetry: prometheus scrape server on %s stopped: %v", addr, err)
+		}
+	}()
+
+	return exp, nil
+}
+
+// newStdoutMetricReader builds a reader that logs metrics to stdout, useful for local
+// debugging of a collector build without a scrape target or OTLP backend on hand.
+func newStdoutMetricReader() (sdkmetric.Reader, error) {
+	exp, err := stdoutMetricExporter()
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewPeriodicReader(exp), nil
+}
+
+func newInstanceID() string {
+	return uuid.NewString()
+}
+
+func stdoutMetricExporter() (sdkmetric.Exporter, error) {
+	return stdoutmetric.New()
+}
+
+// dropAttributeFilter extends an existing attribute filter, if any, with an extra
+// attribute key to drop, so multiple drop_attributes entries compose instead of clobbering
+// one another.
+func dropAttributeFilter(existing sdkmetric.AttributeFilter, drop string) sdkmetric.AttributeFilter {
+	return func(kv attribute.KeyValue) bool {
+		if string(kv.Key) == drop {
+			return false
+		}
+		if existing != nil {
+			return existing(kv)
+		}
+		return true
+	}
+}
+
+// viewsFromConfig translates the user-authored ViewConfig entries into OTel SDK metric
+// view options, applied only once Metrics.Level reaches Detailed.
+func viewsFromConfig(views []ViewConfig) ([]sdkmetric.Option, error) {
+	opts := make([]sdkmetric.Option, 0, len(views))
+	for _, v := range views {
+		if v.Instrument == "" {
+			return nil, fmt.Errorf("telemetry metrics view is missing an instrument selector")
+		}
+
+		stream := sdkmetric.Stream{Name: v.Rename}
+		for _, attr := range v.DropAttributes {
+			stream.AttributeFilter = dropAttributeFilter(stream.AttributeFilter, attr)
+		}
+		if len(v.Buckets) > 0 {
+			stream.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+		}
+
+		opts = append(opts, sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: v.Instrument},
+			stream,
+		)))
+	}
+	return opts, nil
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOtlpMetricGRPCOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      OtlpConfig
+		wantOpts int
+	}{
+		{
+			name:     "endpoint only, defaults to TLS",
+			cfg:      OtlpConfig{Endpoint: "localhost:4317"},
+			wantOpts: 2, // endpoint, TLS credentials
+		},
+		{
+			name:     "insecure",
+			cfg:      OtlpConfig{Endpoint: "localhost:4317", Insecure: true},
+			wantOpts: 2, // endpoint, insecure
+		},
+		{
+			name:     "insecure with headers and compression",
+			cfg:      OtlpConfig{Endpoint: "localhost:4317", Insecure: true, Headers: map[string]string{"x-api-key": "secret"}, Compression: "gzip"},
+			wantOpts: 4, // endpoint, insecure, headers, compression
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, otlpMetricGRPCOptions(tt.cfg), tt.wantOpts)
+		})
+	}
+}
+
+func TestOtlpMetricHTTPOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      OtlpConfig
+		wantOpts int
+	}{
+		{
+			name:     "endpoint only",
+			cfg:      OtlpConfig{Endpoint: "localhost:4318"},
+			wantOpts: 1,
+		},
+		{
+			name:     "insecure with gzip",
+			cfg:      OtlpConfig{Endpoint: "localhost:4318", Insecure: true, Compression: "gzip"},
+			wantOpts: 3, // endpoint, insecure, compression
+		},
+		{
+			name:     "unsupported compression is ignored",
+			cfg:      OtlpConfig{Endpoint: "localhost:4318", Compression: "snappy"},
+			wantOpts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, otlpMetricHTTPOptions(tt.cfg), tt.wantOpts)
+		})
+	}
+}
+
+func TestOtlpTraceGRPCOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      OtlpConfig
+		wantOpts int
+	}{
+		{
+			name:     "endpoint only, defaults to TLS",
+			cfg:      OtlpConfig{Endpoint: "localhost:4317"},
+			wantOpts: 2,
+		},
+		{
+			name:     "insecure with headers",
+			cfg:      OtlpConfig{Endpoint: "localhost:4317", Insecure: true, Headers: map[string]string{"x-api-key": "secret"}},
+			wantOpts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, otlpTraceGRPCOptions(tt.cfg), tt.wantOpts)
+		})
+	}
+}
+
+func TestOtlpTraceHTTPOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      OtlpConfig
+		wantOpts int
+	}{
+		{
+			name:     "endpoint only",
+			cfg:      OtlpConfig{Endpoint: "localhost:4318"},
+			wantOpts: 1,
+		},
+		{
+			name:     "insecure with gzip",
+			cfg:      OtlpConfig{Endpoint: "localhost:4318", Insecure: true, Compression: "gzip"},
+			wantOpts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, otlpTraceHTTPOptions(tt.cfg), tt.wantOpts)
+		})
+	}
+}
@@ -28,6 +28,16 @@ const (
 	metricsLevelCfg  = "metrics-level"
 	metricsPrefixCfg = "metrics-prefix"
 
+	addInstanceIDCfg = "add-instance-id"
+
+	metricsExporterCfg = "metrics-exporter"
+	tracesExporterCfg  = "traces-exporter"
+	otlpEndpointCfg    = "otlp-endpoint"
+	otlpHeadersCfg     = "otlp-headers"
+	otlpInsecureCfg    = "otlp-insecure"
+	otlpCompressionCfg = "otlp-compression"
+	otlpProtocolCfg    = "otlp-protocol"
+
 	// Telemetry levels
 	//
 	// None indicates that no telemetry data should be collected.
@@ -49,6 +59,15 @@ var (
 	useLegacyMetricsPtr *bool
 	useNewMetricsPtr    *bool
 	addInstanceIDPtr    *bool
+
+	// Command-line flags that control the OTLP self-telemetry push path.
+	metricsExporterPtr *string
+	tracesExporterPtr  *string
+	otlpEndpointPtr    *string
+	otlpHeadersPtr     *string
+	otlpInsecurePtr    *bool
+	otlpCompressionPtr *string
+	otlpProtocolPtr    *string
 )
 
 func Flags(flags *flag.FlagSet) {
@@ -82,9 +101,44 @@ func Flags(flags *flag.FlagSet) {
 	)
 
 	addInstanceIDPtr = flags.Bool(
-		"add-instance-id",
+		addInstanceIDCfg,
 		true,
 		"Flag to control the addition of 'service.instance.id' to the collector metrics.")
+
+	metricsExporterPtr = flags.String(
+		metricsExporterCfg,
+		"prometheus",
+		"Exporter(s) for the collector's own metrics, comma-separated (prometheus, otlp).")
+
+	tracesExporterPtr = flags.String(
+		tracesExporterCfg,
+		"",
+		"Exporter for the collector's own traces (otlp). Empty disables self-tracing.")
+
+	otlpEndpointPtr = flags.String(
+		otlpEndpointCfg,
+		"",
+		"OTLP endpoint to push the collector's own metrics/traces to, e.g. localhost:4317.")
+
+	otlpHeadersPtr = flags.String(
+		otlpHeadersCfg,
+		"",
+		"Comma-separated list of key=value headers to send with OTLP self-telemetry requests.")
+
+	otlpInsecurePtr = flags.Bool(
+		otlpInsecureCfg,
+		false,
+		"Disable TLS when connecting to the OTLP self-telemetry endpoint.")
+
+	otlpCompressionPtr = flags.String(
+		otlpCompressionCfg,
+		"",
+		"Compression to use for OTLP self-telemetry requests, e.g. gzip.")
+
+	otlpProtocolPtr = flags.String(
+		otlpProtocolCfg,
+		"grpc",
+		"Transport protocol for the OTLP self-telemetry exporters (grpc, http/protobuf).")
 }
 
 // GetMetricsAddrDefault returns the default metrics bind address and port depending on
@@ -100,21 +154,34 @@ func GetMetricsAddrDefault() string {
 // Level of telemetry data to be generated.
 type Level int8
 
+// GetAddInstanceID reports whether 'service.instance.id' should be added to the
+// collector's metrics. It defaults to true, matching the flag's own default, when called
+// on a path that never invoked Flags() (e.g. the config-file-driven telemetry path).
 func GetAddInstanceID() bool {
+	if addInstanceIDPtr == nil {
+		return true
+	}
 	return *addInstanceIDPtr
 }
 
 // GetLevel returns the Level represented by the string. The parsing is case-insensitive
 // and it returns error if the string value is unknown.
 func GetLevel() (Level, error) {
-	var level Level
 	var str string
 
 	if metricsLevelPtr != nil {
-		str = strings.ToLower(*metricsLevelPtr)
+		str = *metricsLevelPtr
 	}
 
-	switch str {
+	return levelFromString(str)
+}
+
+// levelFromString parses a Level out of its case-insensitive string representation, shared
+// by the flag-driven GetLevel and the config-driven LoadFromViper and NewMeterProvider.
+func levelFromString(str string) (Level, error) {
+	var level Level
+
+	switch strings.ToLower(str) {
 	case "none":
 		level = None
 	case "", "basic":
@@ -134,7 +201,12 @@ func GetMetricsAddr() string {
 	return *metricsAddrPtr
 }
 
+// GetMetricsPrefix returns the configured metrics instrument name prefix, defaulting to
+// "otelcol" when called on a path that never invoked Flags().
 func GetMetricsPrefix() string {
+	if metricsPrefixPtr == nil {
+		return "otelcol"
+	}
 	return *metricsPrefixPtr
 }
 
@@ -145,3 +217,94 @@ func UseLegacyMetrics() bool {
 func UseNewMetrics() bool {
 	return *useNewMetricsPtr
 }
+
+// GetMetricsExporter returns the comma-separated list of exporters configured to carry the
+// collector's own metrics, e.g. "prometheus" or "prometheus,otlp". It defaults to
+// "prometheus", matching the flag's own default, when called on a path that never
+// invoked Flags() (e.g. the config-file-driven telemetry path).
+func GetMetricsExporter() []string {
+	if metricsExporterPtr == nil {
+		return []string{"prometheus"}
+	}
+	return splitAndTrim(*metricsExporterPtr)
+}
+
+// GetTracesExporter returns the comma-separated list of exporters configured to carry the
+// collector's own traces. An empty list means self-tracing is disabled, which is also the
+// default when called on a path that never invoked Flags().
+func GetTracesExporter() []string {
+	if tracesExporterPtr == nil {
+		return nil
+	}
+	return splitAndTrim(*tracesExporterPtr)
+}
+
+// GetOtlpEndpoint returns the endpoint that the OTLP self-telemetry exporters push to.
+// An empty string means no OTLP exporter should be instantiated, which is also the
+// default when called on a path that never invoked Flags().
+func GetOtlpEndpoint() string {
+	if otlpEndpointPtr == nil {
+		return ""
+	}
+	return *otlpEndpointPtr
+}
+
+// GetOtlpHeaders parses the otlp-headers flag into a map of request headers to attach
+// to every OTLP self-telemetry export.
+func GetOtlpHeaders() map[string]string {
+	headers := map[string]string{}
+	if otlpHeadersPtr == nil {
+		return headers
+	}
+	for _, kv := range splitAndTrim(*otlpHeadersPtr) {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return headers
+}
+
+// GetOtlpInsecure reports whether the OTLP self-telemetry exporters should skip TLS. It
+// defaults to false, matching the flag's own default, when called on a path that never
+// invoked Flags().
+func GetOtlpInsecure() bool {
+	if otlpInsecurePtr == nil {
+		return false
+	}
+	return *otlpInsecurePtr
+}
+
+// GetOtlpCompression returns the compression algorithm requested for OTLP self-telemetry
+// exports, e.g. "gzip". An empty string disables compression, which is also the default
+// when called on a path that never invoked Flags().
+func GetOtlpCompression() string {
+	if otlpCompressionPtr == nil {
+		return ""
+	}
+	return *otlpCompressionPtr
+}
+
+// GetOtlpProtocol returns the configured OTLP self-telemetry transport, either "grpc" or
+// "http/protobuf". It defaults to "grpc", matching the flag's own default, when called on
+// a path that never invoked Flags().
+func GetOtlpProtocol() string {
+	if otlpProtocolPtr == nil {
+		return "grpc"
+	}
+	return *otlpProtocolPtr
+}
+
+func splitAndTrim(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}